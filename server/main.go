@@ -6,37 +6,86 @@ import (
 	"time"
 
 	"github.com/jrockway/display/server/pages"
+	"github.com/jrockway/display/server/pages/prefetch"
+	"github.com/jrockway/display/server/pages/sinks"
+	"github.com/jrockway/opinionated-server/client"
 	"github.com/jrockway/opinionated-server/server"
 	"go.uber.org/zap"
 )
 
+// prefetchTimeout bounds how long any single scheduled fetch (an InfluxDB query or a weather
+// provider lookup) may run before the prefetch scheduler gives up on it.
+const prefetchTimeout = 15 * time.Second
+
 func main() {
 	server.AppName = "display"
 
 	influxConfig := new(pages.InfluxDBConfig)
 	outputConfig := new(pages.OutputConfig)
+	weatherConfig := new(pages.WeatherConfig)
+	mqttConfig := new(sinks.MQTTConfig)
 	server.AddFlagGroup("InfluxDB", influxConfig)
 	server.AddFlagGroup("Output", outputConfig)
+	server.AddFlagGroup("Weather", weatherConfig)
+	server.AddFlagGroup("MQTT", mqttConfig)
 	server.Setup()
 
-	display := pages.New(influxConfig, outputConfig)
+	cl := &http.Client{Transport: client.WrapRoundTripper(http.DefaultTransport)}
+	scheduler := prefetch.NewScheduler(prefetchTimeout)
+	pageSet := pages.NewPageSet(outputConfig, []pages.Page{
+		pages.NewIndoorPage(influxConfig, outputConfig, scheduler),
+		pages.NewOutdoorPage(weatherConfig.Providers(cl), scheduler),
+	})
+	scheduler.Start()
+
+	var pageSinks []sinks.Sink
+	if mqttConfig.BrokerURL != "" {
+		mqttSink, err := sinks.NewMQTT(mqttConfig)
+		if err != nil {
+			zap.L().Fatal("problem connecting to mqtt broker", zap.Error(err))
+		}
+		pageSinks = append(pageSinks, mqttSink)
+	}
+
 	mux := http.NewServeMux()
 	server.SetHTTPHandler(mux)
-	mux.HandleFunc("/index.json", display.ServeJSON)
-	mux.HandleFunc("/index.png", display.ServePNG)
-	mux.HandleFunc("/large.png", display.ServeLargePNG)
+	mux.HandleFunc("/index.json", pageSet.ServeJSON)
+	mux.HandleFunc("/index.png", pageSet.ServeImage)
+	mux.HandleFunc("/large.png", pageSet.ServeLargePNG)
+	mux.HandleFunc("/pages/", pageSet.ServeNamedImage)
+	mux.HandleFunc("/debug/prefetch", scheduler.ServeHTTP)
+
+	// Keep every page's rendered image fresh. This no longer triggers any network I/O: each
+	// page's Update just copies whatever the prefetch scheduler has most recently cached, so
+	// this tick can run much faster than the sources themselves are actually fetched.
 	go func() {
-		interval := 10 * time.Second
+		interval := 2 * time.Second
 		t := time.NewTicker(interval)
 		for {
 			ctx, c := context.WithTimeout(context.Background(), interval)
-			if err := display.UpdateOnce(ctx); err != nil {
-				zap.L().Warn("problem updating display", zap.Error(err))
+			if err := pageSet.UpdateAll(ctx); err != nil {
+				zap.L().Warn("problem updating pages", zap.Error(err))
+			}
+			img, text := pageSet.ActiveSnapshot()
+			if img != nil {
+				for _, sink := range pageSinks {
+					if err := sink.Publish(ctx, img, text); err != nil {
+						zap.L().Warn("problem publishing to sink", zap.Error(err))
+					}
+				}
 			}
 			c()
 			<-t.C
 		}
 	}()
 
+	// Rotate through the pages, showing each for its own preferred duration.
+	go func() {
+		for {
+			time.Sleep(pageSet.ActivePage().PreferredDuration())
+			pageSet.Advance()
+		}
+	}()
+
 	server.ListenAndServe()
 }
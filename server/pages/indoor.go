@@ -0,0 +1,258 @@
+package pages
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"net/http"
+	"sync"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/jrockway/display/server/pages/prefetch"
+	"github.com/jrockway/opinionated-server/client"
+	"go.uber.org/zap"
+)
+
+type InfluxDBConfig struct {
+	Address           string `long:"influxdb_address" env:"INFLUXDB_ADDRESS" default:"http://localhost:8086" description:"The address of the InfluxDB server to read from."`
+	Token             string `long:"influxdb_token" env:"INFLUXDB_TOKEN" description:"An access token to query the provided InfluxDB server."`
+	Org               string `long:"influxdb_org" env:"INFLUXDB_ORG" description:"The org that your data is in."`
+	MetricsConfigPath string `long:"metrics_config" env:"METRICS_CONFIG" description:"Path to a YAML file describing the metrics to read and how to lay them out on the indoor page. If unset, the built-in indoor temperature/humidity layout is used."`
+	//SensorBucket string `long:"influxdb_bucket" env:"INFLUXDB_SENSOR_BUCKET" default:"home-sensors" description:"The bucket to query sensor data from."`
+}
+
+// indoorPageDuration is how long the indoor page stays active once PageSet rotates to it.
+const indoorPageDuration = 10 * time.Second
+
+// MetricValue is a MetricSpec's most recently fetched reading.
+type MetricValue struct {
+	Current    float64
+	WindowMean float64
+	History    []float64
+	Timestamp  time.Time
+}
+
+// indoorPrefetchName is the prefetch cache key for a given indoor metric.
+func indoorPrefetchName(metric string) string { return "indoor:" + metric }
+
+// IndoorPage shows a config-driven set of InfluxDB-backed metrics, such as indoor temperature and
+// relative humidity. The metrics themselves are fetched on their own schedule by a
+// prefetch.Scheduler; Update only copies whatever that scheduler has most recently cached.
+type IndoorPage struct {
+	sync.RWMutex
+	scheduler  *prefetch.Scheduler
+	specs      []MetricSpec
+	specByName map[string]MetricSpec
+	cells      []ScreenCell
+	values     map[string]*MetricValue
+}
+
+// NewIndoorPage returns a Page that reads the metrics described by icfg.MetricsConfigPath (or the
+// built-in temperature/humidity metrics, if unset) from the InfluxDB server described by icfg. It
+// registers one prefetch fetcher per metric with scheduler; the caller is responsible for
+// starting scheduler.
+func NewIndoorPage(icfg *InfluxDBConfig, ocfg *OutputConfig, scheduler *prefetch.Scheduler) *IndoorPage {
+	specs, cells := defaultMetricsConfig()
+	if icfg.MetricsConfigPath != "" {
+		if loaded, loadedCells, err := LoadMetricsConfig(icfg.MetricsConfigPath); err != nil {
+			zap.L().Warn("problem loading metrics config; falling back to built-in metrics", zap.String("path", icfg.MetricsConfigPath), zap.Error(err))
+		} else {
+			specs, cells = loaded, loadedCells
+		}
+	}
+
+	cl := &http.Client{
+		Transport: client.WrapRoundTripper(http.DefaultTransport),
+	}
+	opts := influxdb2.DefaultOptions()
+	opts.SetHTTPClient(cl)
+	ic := influxdb2.NewClientWithOptions(icfg.Address, icfg.Token, opts)
+	influxClient := ic.QueryAPI(icfg.Org)
+
+	specByName := make(map[string]MetricSpec, len(specs))
+	for _, s := range specs {
+		specByName[s.Name] = s
+	}
+
+	p := &IndoorPage{
+		scheduler:  scheduler,
+		specs:      specs,
+		specByName: specByName,
+		cells:      cells,
+		values:     make(map[string]*MetricValue, len(specs)),
+	}
+	for _, spec := range specs {
+		spec := spec
+		cron := spec.Cron
+		if cron == "" {
+			cron = defaultMetricCron
+		}
+		fetch := func(ctx context.Context) (interface{}, error) {
+			return updateMetric(ctx, influxClient, spec)
+		}
+		if err := scheduler.Register(indoorPrefetchName(spec.Name), cron, 2*spec.TrendWindow, fetch); err != nil {
+			zap.L().Warn("problem scheduling indoor metric fetch", zap.String("metric", spec.Name), zap.Error(err))
+		}
+	}
+	return p
+}
+
+func (p *IndoorPage) Name() string { return "indoor" }
+
+func (p *IndoorPage) PreferredDuration() time.Duration { return indoorPageDuration }
+
+// queryOneFloat runs the provided Flux query and returns its first row's value.
+func queryOneFloat(ctx context.Context, influxClient api.QueryAPI, query string) (float64, error) {
+	result, err := influxClient.Query(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("query: %w", err)
+	}
+	defer result.Close()
+	if !result.Next() {
+		return 0, fmt.Errorf("no rows (possibly caused by: %v)", result.Err())
+	}
+	rec := result.Record()
+	if rec == nil {
+		return 0, fmt.Errorf("nil record (possibly caused by: %v)", result.Err())
+	}
+	v, ok := rec.Value().(float64)
+	if !ok {
+		return 0, fmt.Errorf("record value %#v is not a float64", rec.Value())
+	}
+	return v, nil
+}
+
+// querySeriesInflux runs the provided Flux query and returns every row's value as a float64, in
+// the order InfluxDB returns them. Rows whose value isn't a float64 are skipped.
+func querySeriesInflux(ctx context.Context, influxClient api.QueryAPI, query string) ([]float64, error) {
+	result, err := influxClient.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer result.Close()
+	var vals []float64
+	for result.Next() {
+		rec := result.Record()
+		if rec == nil {
+			continue
+		}
+		if v, ok := rec.Value().(float64); ok {
+			vals = append(vals, v)
+		}
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("iterate results: %w", err)
+	}
+	return vals, nil
+}
+
+// updateMetric runs the three queries (current value, window mean, and per-minute history)
+// needed to produce a MetricValue for spec. It is what each metric's prefetch fetcher runs.
+func updateMetric(ctx context.Context, influxClient api.QueryAPI, spec MetricSpec) (*MetricValue, error) {
+	window := spec.TrendWindow.String()
+	ranged := fmt.Sprintf(spec.Flux, window)
+
+	aggregate := spec.Aggregate
+	if aggregate == "" {
+		aggregate = "last"
+	}
+	currentQuery := ranged + "\n|> last()"
+	if aggregate != "last" {
+		currentQuery = ranged + fmt.Sprintf("\n|> %s()\n|> last()", aggregate)
+	}
+	current, err := queryOneFloat(ctx, influxClient, currentQuery)
+	if err != nil {
+		return nil, fmt.Errorf("current value: %w", err)
+	}
+
+	mean, err := queryOneFloat(ctx, influxClient, ranged+"\n|> mean()\n|> last()")
+	if err != nil {
+		return nil, fmt.Errorf("window mean: %w", err)
+	}
+
+	history, err := querySeriesInflux(ctx, influxClient, ranged+"\n|> aggregateWindow(every: 1m, fn: mean)")
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+
+	return &MetricValue{Current: current, WindowMean: mean, History: history, Timestamp: time.Now()}, nil
+}
+
+// Update copies each metric's most recently prefetched value into p. It performs no network I/O
+// of its own; the actual InfluxDB queries run on the schedule given to NewIndoorPage.
+func (p *IndoorPage) Update(ctx context.Context) error {
+	values := make(map[string]*MetricValue, len(p.specs))
+	for _, spec := range p.specs {
+		if v, _, ok := prefetch.Get[*MetricValue](p.scheduler, indoorPrefetchName(spec.Name)); ok {
+			values[spec.Name] = v
+		}
+	}
+
+	p.Lock()
+	for name, v := range values {
+		p.values[name] = v
+	}
+	p.Unlock()
+	return nil
+}
+
+// formatValue renders v per spec's Format, appending a trend arrow byte if showArrow is set and
+// the current value differs from the window mean by more than spec.TrendThreshold.
+func formatValue(spec MetricSpec, v *MetricValue, showArrow bool) []byte {
+	format := spec.Format
+	if format == "" {
+		format = "%.1f"
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, format, v.Current)
+	if showArrow {
+		arrow := byte(' ')
+		if chg := v.Current - v.WindowMean; chg < -spec.TrendThreshold {
+			arrow = 25
+		} else if chg > spec.TrendThreshold {
+			arrow = 24
+		}
+		buf.WriteByte(arrow)
+	}
+	return buf.Bytes()
+}
+
+func (p *IndoorPage) Render(dst *image.RGBA) error {
+	p.RLock()
+	defer p.RUnlock()
+
+	sf := newSmallFont(dst)
+	width := dst.Bounds().Dx()
+	for _, cell := range p.cells {
+		v := p.values[cell.Metric]
+		if v == nil {
+			continue
+		}
+		spec := p.specByName[cell.Metric]
+		if err := sf.Draw(formatValue(spec, v, cell.ShowTrendArrow), cell.X, cell.Y); err != nil {
+			return fmt.Errorf("draw %s: %w", cell.Metric, err)
+		}
+		if cell.ShowSparkline && width > sparklineWidth {
+			rect := image.Rect(width-sparklineWidth, cell.Y, width, cell.Y+8)
+			drawSparkline(dst, rect, v.History)
+		}
+	}
+	return nil
+}
+
+func (p *IndoorPage) ScreenText() [][]byte {
+	p.RLock()
+	defer p.RUnlock()
+	var lines [][]byte
+	for _, cell := range p.cells {
+		v := p.values[cell.Metric]
+		if v == nil {
+			continue
+		}
+		lines = append(lines, formatValue(p.specByName[cell.Metric], v, cell.ShowTrendArrow))
+	}
+	return lines
+}
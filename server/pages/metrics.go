@@ -0,0 +1,104 @@
+package pages
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// MetricSpec describes one InfluxDB-backed reading: how to query it, how to aggregate it into a
+// single current value, and how to format it for display.
+type MetricSpec struct {
+	Name string `yaml:"name"`
+
+	// Flux is a query that ranges, filters, and maps the raw series, but performs no
+	// aggregation of its own. It must contain exactly one "%s" verb, which is replaced with
+	// the Flux duration literal for TrendWindow (e.g. "1h0m0s").
+	Flux string `yaml:"flux"`
+
+	// Aggregate is the Flux aggregate function used to reduce Flux's output to this metric's
+	// current value: "last", "mean", "max", or "min".
+	Aggregate string `yaml:"aggregate"`
+
+	// Format is a fmt verb used to render the current value, e.g. "%.1f".
+	Format string `yaml:"format"`
+
+	// TrendThreshold is how much the current value must differ from the window mean before a
+	// trend arrow is shown.
+	TrendThreshold float64 `yaml:"trend_threshold"`
+
+	// TrendWindow is both the lookback window for the window mean and the range given to
+	// Flux.
+	TrendWindow time.Duration `yaml:"trend_window"`
+
+	// Cron is the schedule, in robfig/cron syntax, on which this metric is refetched from
+	// InfluxDB. Defaults to "@every 1m" if empty.
+	Cron string `yaml:"cron"`
+}
+
+// defaultMetricCron is the refetch schedule used when a MetricSpec doesn't set Cron.
+const defaultMetricCron = "@every 1m"
+
+// ScreenCell places one metric's reading on the page.
+type ScreenCell struct {
+	Metric         string `yaml:"metric"`
+	X              int    `yaml:"x"`
+	Y              int    `yaml:"y"`
+	ShowTrendArrow bool   `yaml:"show_trend_arrow"`
+	ShowSparkline  bool   `yaml:"show_sparkline"`
+}
+
+type metricsConfig struct {
+	Metrics []MetricSpec `yaml:"metrics"`
+	Screen  []ScreenCell `yaml:"screen"`
+}
+
+// LoadMetricsConfig reads a YAML file describing MetricSpecs and their ScreenCell layout.
+func LoadMetricsConfig(path string) ([]MetricSpec, []ScreenCell, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	var cfg metricsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfg.Metrics, cfg.Screen, nil
+}
+
+// defaultMetricsConfig is what IndoorPage uses when no metrics config file is available: the
+// indoor temperature and relative humidity readings this module has always shown.
+func defaultMetricsConfig() ([]MetricSpec, []ScreenCell) {
+	specs := []MetricSpec{
+		{
+			Name: "temperature",
+			Flux: `from(bucket: "home-sensors")
+				  |> range(start: -%s, stop: now())
+				  |> filter(fn: (r) => r._measurement == "environment" and r._field == "temperature")
+				  |> map(fn: (r) => ({ r with _value: float(v: r._value) / 1000000000.0 - 273.15 }))
+				  |> map(fn: (r) => ({ r with _value: r._value * 1.8 + 32.0 }))`,
+			Aggregate:      "last",
+			Format:         "%.1f",
+			TrendThreshold: 0.5,
+			TrendWindow:    time.Hour,
+		},
+		{
+			Name: "humidity",
+			Flux: `from(bucket: "home-sensors")
+				  |> range(start: -%s, stop: now())
+				  |> filter(fn: (r) => r._measurement == "environment" and r._field == "relative_humidity")
+				  |> map(fn: (r) => ({ r with _value: float(v: r._value) / 100000.0 }))`,
+			Aggregate:      "last",
+			Format:         "%.0f",
+			TrendThreshold: 0.5,
+			TrendWindow:    time.Hour,
+		},
+	}
+	cells := []ScreenCell{
+		{Metric: "temperature", X: 0, Y: 0, ShowTrendArrow: true, ShowSparkline: true},
+		{Metric: "humidity", X: 0, Y: 8, ShowTrendArrow: true, ShowSparkline: true},
+	}
+	return specs, cells
+}
@@ -0,0 +1,67 @@
+package pages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFormatValue(t *testing.T) {
+	spec := MetricSpec{Format: "%.1f", TrendThreshold: 0.5}
+
+	testCases := []struct {
+		name      string
+		v         *MetricValue
+		showArrow bool
+		want      string
+	}{
+		{"no arrow", &MetricValue{Current: 72.3}, false, "72.3"},
+		{"rising", &MetricValue{Current: 72.3, WindowMean: 70}, true, "72.3\x18"},
+		{"falling", &MetricValue{Current: 68, WindowMean: 70}, true, "68.0\x19"},
+		{"flat", &MetricValue{Current: 70.2, WindowMean: 70}, true, "70.2 "},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(formatValue(spec, tc.v, tc.showArrow)); got != tc.want {
+				t.Errorf("formatValue:\n  got:  %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadMetricsConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.yaml")
+	const config = `
+metrics:
+  - name: pressure
+    flux: |
+      from(bucket: "home-sensors")
+        |> range(start: -%s, stop: now())
+    aggregate: mean
+    format: "%.2f"
+    trend_threshold: 1
+    trend_window: 30m
+screen:
+  - metric: pressure
+    x: 0
+    y: 16
+    show_trend_arrow: true
+    show_sparkline: false
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	specs, cells, err := LoadMetricsConfig(path)
+	if err != nil {
+		t.Fatalf("LoadMetricsConfig: %v", err)
+	}
+	if len(specs) != 1 || specs[0].Name != "pressure" || specs[0].TrendWindow != 30*time.Minute {
+		t.Errorf("specs:\n  got:  %+v", specs)
+	}
+	if len(cells) != 1 || cells[0].Metric != "pressure" || cells[0].Y != 16 {
+		t.Errorf("cells:\n  got:  %+v", cells)
+	}
+}
@@ -0,0 +1,187 @@
+package pages
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jrockway/display/server/pages/prefetch"
+	"github.com/jrockway/display/server/weather"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var weatherProviderRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "weather_provider_requests_total",
+	Help: "Count of outdoor weather provider queries, by provider name and result.",
+}, []string{"provider", "result"})
+
+// weatherProviderTimeout bounds how long we wait on any single weather provider before falling
+// back to the next one.
+const weatherProviderTimeout = 5 * time.Second
+
+// outdoorPageDuration is how long the outdoor page stays active once PageSet rotates to it.
+const outdoorPageDuration = 10 * time.Second
+
+// outdoorPrefetchName is the prefetch cache key for the outdoor reading.
+const outdoorPrefetchName = "outdoor:temperature"
+
+// outdoorFetchCron is how often the outdoor weather providers are polled. None of them get data
+// more frequently than this, so polling faster would just be rude to upstream.
+const outdoorFetchCron = "@every 6m"
+
+// outdoorReading is the prefetched result of walking the weather.Provider fallback chain.
+type outdoorReading struct {
+	Temperature         float64
+	LastHourTemperature float64
+	History             []float64
+	Time                time.Time
+}
+
+// WeatherConfig configures the outdoor weather providers that OutdoorPage falls back across.
+// Providers are tried in the order listed here: NYS Mesonet, Open-Meteo, METAR, then wttr.in.
+type WeatherConfig struct {
+	MesonetStation string  `long:"weather_mesonet_station" env:"WEATHER_MESONET_STATION" default:"bkln" description:"The NYS Mesonet station ID to read outdoor temperature from."`
+	Latitude       float64 `long:"weather_latitude" env:"WEATHER_LATITUDE" default:"40.6782" description:"Latitude to use for coordinate-based weather providers (Open-Meteo)."`
+	Longitude      float64 `long:"weather_longitude" env:"WEATHER_LONGITUDE" default:"-73.9442" description:"Longitude to use for coordinate-based weather providers (Open-Meteo)."`
+	METARStation   string  `long:"weather_metar_station" env:"WEATHER_METAR_STATION" default:"KJFK" description:"The ICAO airport code to read METAR observations from."`
+	WttrLocation   string  `long:"weather_wttr_location" env:"WEATHER_WTTR_LOCATION" default:"New York" description:"The place name to query wttr.in for, used as a last resort."`
+}
+
+// Providers builds the default outdoor weather provider fallback chain from cfg, using cl to
+// make HTTP requests.
+func (cfg *WeatherConfig) Providers(cl *http.Client) []weather.Provider {
+	return []weather.Provider{
+		weather.NewNYSMesonet(cl, cfg.MesonetStation),
+		weather.NewOpenMeteo(cl, cfg.Latitude, cfg.Longitude),
+		weather.NewMETAR(cl, cfg.METARStation),
+		weather.NewWttr(cl, cfg.WttrLocation),
+	}
+}
+
+// OutdoorPage shows outdoor temperature, read from a fallback chain of weather.Providers. The
+// providers are polled on their own schedule by a prefetch.Scheduler; Update only copies whatever
+// that scheduler has most recently cached.
+type OutdoorPage struct {
+	sync.RWMutex
+	scheduler *prefetch.Scheduler
+
+	OutdoorTemperature         float64
+	LastHourOutdoorTemperature float64
+	OutdoorTemperatureHistory  []float64
+	OutdoorLastData            time.Time
+}
+
+// NewOutdoorPage returns a Page that reads outdoor conditions from providers, tried in order. It
+// registers a prefetch fetcher with scheduler; the caller is responsible for starting scheduler.
+func NewOutdoorPage(providers []weather.Provider, scheduler *prefetch.Scheduler) *OutdoorPage {
+	fetch := func(ctx context.Context) (interface{}, error) {
+		return fetchOutdoorReading(ctx, providers)
+	}
+	if err := scheduler.Register(outdoorPrefetchName, outdoorFetchCron, 2*6*time.Minute, fetch); err != nil {
+		zap.L().Warn("problem scheduling outdoor weather fetch", zap.Error(err))
+	}
+	return &OutdoorPage{scheduler: scheduler}
+}
+
+func (p *OutdoorPage) Name() string { return "outdoor" }
+
+func (p *OutdoorPage) PreferredDuration() time.Duration { return outdoorPageDuration }
+
+// fetchOutdoorReading walks providers in order, returning the first one whose Current succeeds.
+// It is what the outdoor prefetch fetcher runs.
+//
+// A provider is accepted on Current alone: LastHour failing just means no history is available
+// (the documented behavior for providers like wttr.in, per weather.Provider's doc comment), not
+// that the provider itself is down. Treating a LastHour failure as total failure would mean the
+// fallback chain could never actually reach a history-less "last resort" provider such as
+// wttr.in, since it would always be skipped in favor of returning an error.
+func fetchOutdoorReading(ctx context.Context, providers []weather.Provider) (*outdoorReading, error) {
+	var errs []error
+	for _, provider := range providers {
+		pctx, cancel := context.WithTimeout(ctx, weatherProviderTimeout)
+		cur, err := provider.Current(pctx)
+		if err != nil {
+			cancel()
+			weatherProviderRequests.WithLabelValues(provider.Name(), "error").Inc()
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			zap.L().Debug("weather provider failed", zap.String("provider", provider.Name()), zap.Error(err))
+			continue
+		}
+
+		hist, err := provider.LastHour(pctx)
+		cancel()
+		lastHourTemperature := cur.TemperatureF
+		var history []float64
+		if err != nil {
+			zap.L().Debug("weather provider has no history", zap.String("provider", provider.Name()), zap.Error(err))
+		} else {
+			lastHourTemperature = weather.Mean(hist)
+			history = make([]float64, len(hist))
+			for i, o := range hist {
+				history[i] = o.TemperatureF
+			}
+		}
+
+		weatherProviderRequests.WithLabelValues(provider.Name(), "success").Inc()
+		return &outdoorReading{
+			Temperature:         cur.TemperatureF,
+			LastHourTemperature: lastHourTemperature,
+			History:             history,
+			Time:                cur.Time,
+		}, nil
+	}
+	return nil, fmt.Errorf("all %d weather providers failed: %v", len(providers), errs)
+}
+
+func (p *OutdoorPage) Update(ctx context.Context) error {
+	v, _, ok := prefetch.Get[*outdoorReading](p.scheduler, outdoorPrefetchName)
+	if !ok {
+		return nil
+	}
+	p.Lock()
+	p.OutdoorTemperature = v.Temperature
+	p.LastHourOutdoorTemperature = v.LastHourTemperature
+	p.OutdoorTemperatureHistory = v.History
+	p.OutdoorLastData = v.Time
+	p.Unlock()
+	return nil
+}
+
+func (p *OutdoorPage) Render(dst *image.RGBA) error {
+	p.RLock()
+	defer p.RUnlock()
+
+	outdoorTempChange := byte(' ')
+	if chg := p.OutdoorTemperature - p.LastHourOutdoorTemperature; chg < -1 {
+		outdoorTempChange = 25
+	} else if chg > 1 {
+		outdoorTempChange = 24
+	}
+
+	sf := newSmallFont(dst)
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("%.1f", p.OutdoorTemperature))
+	buf.WriteByte(outdoorTempChange)
+	if err := sf.Draw(buf.Bytes(), 0, 0); err != nil {
+		return fmt.Errorf("draw outdoor temperature: %w", err)
+	}
+
+	width := dst.Bounds().Dx()
+	if width > sparklineWidth {
+		rect := image.Rect(width-sparklineWidth, 0, width, 8)
+		drawSparkline(dst, rect, p.OutdoorTemperatureHistory)
+	}
+	return nil
+}
+
+func (p *OutdoorPage) ScreenText() [][]byte {
+	p.RLock()
+	defer p.RUnlock()
+	return [][]byte{[]byte(fmt.Sprintf("%.1f", p.OutdoorTemperature))}
+}
@@ -0,0 +1,93 @@
+package pages
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jrockway/display/server/weather"
+)
+
+// fakeProvider is a minimal weather.Provider used to exercise fetchOutdoorReading's fallback
+// logic without any real upstreams.
+type fakeProvider struct {
+	name        string
+	current     weather.Observation
+	currentErr  error
+	lastHour    []weather.Observation
+	lastHourErr error
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Current(ctx context.Context) (weather.Observation, error) {
+	return p.current, p.currentErr
+}
+
+func (p *fakeProvider) LastHour(ctx context.Context) ([]weather.Observation, error) {
+	return p.lastHour, p.lastHourErr
+}
+
+func TestFetchOutdoorReading(t *testing.T) {
+	someTime := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		providers []weather.Provider
+		want      *outdoorReading
+		wantErr   bool
+	}{
+		{
+			name: "first provider succeeds",
+			providers: []weather.Provider{
+				&fakeProvider{name: "a", current: weather.Observation{TemperatureF: 70, Time: someTime}, lastHour: []weather.Observation{{TemperatureF: 68}, {TemperatureF: 72}}},
+			},
+			want: &outdoorReading{Temperature: 70, LastHourTemperature: 70, History: []float64{68, 72}, Time: someTime},
+		},
+		{
+			name: "falls back past a failing provider",
+			providers: []weather.Provider{
+				&fakeProvider{name: "a", currentErr: errors.New("down")},
+				&fakeProvider{name: "b", current: weather.Observation{TemperatureF: 55, Time: someTime}, lastHour: []weather.Observation{{TemperatureF: 55}}},
+			},
+			want: &outdoorReading{Temperature: 55, LastHourTemperature: 55, History: []float64{55}, Time: someTime},
+		},
+		{
+			name: "provider with Current but no LastHour is still accepted",
+			providers: []weather.Provider{
+				&fakeProvider{name: "a", currentErr: errors.New("down")},
+				&fakeProvider{name: "wttr", current: weather.Observation{TemperatureF: 80, Time: someTime}, lastHourErr: errors.New("wttr.in does not provide historical observations")},
+			},
+			want: &outdoorReading{Temperature: 80, LastHourTemperature: 80, History: nil, Time: someTime},
+		},
+		{
+			name: "all providers fail",
+			providers: []weather.Provider{
+				&fakeProvider{name: "a", currentErr: errors.New("down")},
+				&fakeProvider{name: "b", currentErr: errors.New("also down")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := fetchOutdoorReading(context.Background(), tc.providers)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("fetchOutdoorReading: got nil error, want non-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fetchOutdoorReading: %v", err)
+			}
+			if got.Temperature != tc.want.Temperature || got.LastHourTemperature != tc.want.LastHourTemperature || !got.Time.Equal(tc.want.Time) {
+				t.Errorf("reading:\n  got:  %+v\n want: %+v", got, tc.want)
+			}
+			if len(got.History) != len(tc.want.History) {
+				t.Errorf("history:\n  got:  %v\n want: %v", got.History, tc.want.History)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package pages
+
+import (
+	"context"
+	"image"
+	"time"
+)
+
+// Page is a single thing the display can show: a set of related readings, rendered into a
+// fixed-size image. PageSet rotates through a configured list of Pages.
+type Page interface {
+	// Update refreshes the page's data from its upstream source(s).
+	Update(ctx context.Context) error
+
+	// Render draws the page's current data onto dst, which is already sized to the
+	// configured display dimensions.
+	Render(dst *image.RGBA) error
+
+	// Name identifies the page, for the /pages/{name}.png route and logging.
+	Name() string
+
+	// PreferredDuration is how long the page should stay on screen once it's active.
+	PreferredDuration() time.Duration
+}
+
+// TextPage is implemented by pages that can also express their current reading as lines of text,
+// for ServeJSON and the MQTT text sink.
+type TextPage interface {
+	ScreenText() [][]byte
+}
@@ -0,0 +1,279 @@
+package pages
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"golang.org/x/image/bmp"
+)
+
+type OutputConfig struct {
+	Width  int `long:"display_width" env:"DISPLAY_WIDTH" default:"64" description:"The width, in pixels, of the target display."`
+	Height int `long:"display_height" env:"DISPLAY_HEIGHT" default:"32" description:"The height, in pixels, of the target display."`
+}
+
+// PageSet rotates through a configured list of Pages, serving the currently-active one's
+// rendered image while keeping every page's own image fresh in the background.
+type PageSet struct {
+	mu           sync.RWMutex
+	outputConfig *OutputConfig
+	pages        []Page
+	byName       map[string]int
+	active       int
+	images       []*image.RGBA
+}
+
+// NewPageSet returns a PageSet that rotates through pages in the given order.
+func NewPageSet(ocfg *OutputConfig, pages []Page) *PageSet {
+	byName := make(map[string]int, len(pages))
+	for i, p := range pages {
+		byName[p.Name()] = i
+	}
+	return &PageSet{
+		outputConfig: ocfg,
+		pages:        pages,
+		byName:       byName,
+		images:       make([]*image.RGBA, len(pages)),
+	}
+}
+
+// UpdateIndex refreshes and re-renders the page at idx.
+func (ps *PageSet) UpdateIndex(ctx context.Context, idx int) error {
+	ps.mu.RLock()
+	p := ps.pages[idx]
+	ps.mu.RUnlock()
+
+	if err := p.Update(ctx); err != nil {
+		return fmt.Errorf("update %s: %w", p.Name(), err)
+	}
+	img := newBlankImage(ps.outputConfig.Width, ps.outputConfig.Height)
+	if err := p.Render(img); err != nil {
+		return fmt.Errorf("render %s: %w", p.Name(), err)
+	}
+	ps.mu.Lock()
+	ps.images[idx] = img
+	ps.mu.Unlock()
+	return nil
+}
+
+// UpdateAll refreshes and re-renders every page, concurrently.
+func (ps *PageSet) UpdateAll(ctx context.Context) error {
+	ps.mu.RLock()
+	n := len(ps.pages)
+	ps.mu.RUnlock()
+
+	doneCh := make(chan error)
+	for i := 0; i < n; i++ {
+		i := i
+		go func() { doneCh <- ps.UpdateIndex(ctx, i) }()
+	}
+	var errs []error
+	for i := 0; i < n; i++ {
+		if err := <-doneCh; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		var strs []string
+		for _, err := range errs {
+			strs = append(strs, err.Error())
+		}
+		return fmt.Errorf("%d errors: %v", len(errs), strings.Join(strs, "\n"))
+	}
+	return nil
+}
+
+// Advance moves the active page to the next one in rotation order, wrapping around.
+func (ps *PageSet) Advance() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if len(ps.pages) == 0 {
+		return
+	}
+	ps.active = (ps.active + 1) % len(ps.pages)
+}
+
+// ActiveIndex returns the index of the currently-active page.
+func (ps *PageSet) ActiveIndex() int {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return ps.active
+}
+
+// ActivePage returns the currently-active page.
+func (ps *PageSet) ActivePage() Page {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return ps.pages[ps.active]
+}
+
+// ActiveSnapshot returns the currently-active page's last-rendered image and screen text
+// (nil if the page doesn't implement TextPage), for sinks to publish.
+func (ps *PageSet) ActiveSnapshot() (*image.RGBA, [][]byte) {
+	ps.mu.RLock()
+	p := ps.pages[ps.active]
+	img := ps.images[ps.active]
+	ps.mu.RUnlock()
+
+	var text [][]byte
+	if tp, ok := p.(TextPage); ok {
+		text = tp.ScreenText()
+	}
+	return img, text
+}
+
+// resolveIndex returns the page index requested by req's "page" query param, or the active page
+// if none was given or it was out of range.
+func (ps *PageSet) resolveIndex(req *http.Request) int {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	if raw := req.URL.Query().Get("page"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 && n < len(ps.pages) {
+			return n
+		}
+	}
+	return ps.active
+}
+
+func (ps *PageSet) image(idx int) *image.RGBA {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return ps.images[idx]
+}
+
+type pageAsJSON struct {
+	Page   string   `json:"page"`
+	Screen []string `json:"screen"`
+}
+
+// ServeJSON serves the screen text of the page selected by the "page" query param (or the active
+// page), for pages that implement TextPage.
+func (ps *PageSet) ServeJSON(w http.ResponseWriter, req *http.Request) {
+	idx := ps.resolveIndex(req)
+	ps.mu.RLock()
+	p := ps.pages[idx]
+	ps.mu.RUnlock()
+
+	disp := &pageAsJSON{Page: p.Name()}
+	if tp, ok := p.(TextPage); ok {
+		for _, l := range tp.ScreenText() {
+			disp.Screen = append(disp.Screen, string(l))
+		}
+	}
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(disp); err != nil {
+		l := ctxzap.Extract(req.Context())
+		l.Info("error sending json to client", zap.Error(err))
+	}
+}
+
+func enlargedImage(src *image.RGBA, enlarge, space int) *image.RGBA {
+	if src == nil {
+		src = image.NewRGBA(image.Rect(0, 0, 1, 1))
+	}
+	img := image.NewRGBA(image.Rect(0, 0, enlarge*src.Bounds().Dx(), enlarge*src.Bounds().Dy()))
+	for x := 0; x < src.Bounds().Dx(); x++ {
+		for y := 0; y < src.Bounds().Dy(); y++ {
+			val := src.At(x, y)
+			for i := space; i < enlarge-space; i++ {
+				for j := space; j < enlarge-space; j++ {
+					img.Set(x*enlarge+i, y*enlarge+j, val)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// ServeLargePNG serves an enlarged PNG of the page selected by the "page" query param (or the
+// active page).
+func (ps *PageSet) ServeLargePNG(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	idx := ps.resolveIndex(req)
+	img := enlargedImage(ps.image(idx), 16, 2)
+	w.Header().Add("content-type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	if err := png.Encode(w, img); err != nil {
+		l := ctxzap.Extract(ctx)
+		l.Error("problem encoding png", zap.Error(err))
+	}
+}
+
+func encodeImage(w io.Writer, ext string, img image.Image) (string, error) {
+	switch ext {
+	case ".bmp":
+		return "image/bmp", bmp.Encode(w, img)
+	case ".txt":
+		return "text/plain", func() error {
+			for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+				for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+					r, g, b, _ := img.At(x, y).RGBA()
+					if r != 0 || g != 0 || b != 0 {
+						if _, err := w.Write([]byte(fmt.Sprintf("%v %v\n", x, y))); err != nil {
+							return fmt.Errorf("at %v, %v: %v", x, y, err)
+						}
+					}
+				}
+			}
+			return nil
+		}()
+	default:
+		return "image/png", png.Encode(w, img)
+	}
+}
+
+func (ps *PageSet) serveImage(w http.ResponseWriter, req *http.Request, idx int) {
+	ctx := req.Context()
+	img := ps.image(idx)
+	if img == nil {
+		http.Error(w, errors.New("image has not been rendered yet").Error(), http.StatusInternalServerError)
+		return
+	}
+	buf := new(bytes.Buffer)
+	ct, err := encodeImage(buf, path.Ext(req.URL.Path), img)
+	if err != nil {
+		l := ctxzap.Extract(ctx)
+		l.Error("problem encoding image", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("content-type", ct)
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, buf); err != nil {
+		l := ctxzap.Extract(ctx)
+		l.Error("problem copying image to client", zap.Error(err))
+	}
+}
+
+// ServeImage serves the image of the page selected by the "page" query param (or the active
+// page). The response format is chosen by the request path's extension (.png, .bmp, or .txt).
+func (ps *PageSet) ServeImage(w http.ResponseWriter, req *http.Request) {
+	ps.serveImage(w, req, ps.resolveIndex(req))
+}
+
+// ServeNamedImage serves a single pinned page, addressed by name, e.g. "/pages/outdoor.png".
+func (ps *PageSet) ServeNamedImage(w http.ResponseWriter, req *http.Request) {
+	name := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/pages/"), path.Ext(req.URL.Path))
+	ps.mu.RLock()
+	idx, ok := ps.byName[name]
+	ps.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	ps.serveImage(w, req, idx)
+}
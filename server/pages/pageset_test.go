@@ -0,0 +1,104 @@
+package pages
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakePage is a minimal Page (and TextPage) used to exercise PageSet without any real upstreams.
+type fakePage struct {
+	name string
+	fill color.RGBA
+	text [][]byte
+}
+
+func (p *fakePage) Update(ctx context.Context) error { return nil }
+
+func (p *fakePage) Render(dst *image.RGBA) error {
+	for y := dst.Bounds().Min.Y; y < dst.Bounds().Max.Y; y++ {
+		for x := dst.Bounds().Min.X; x < dst.Bounds().Max.X; x++ {
+			dst.SetRGBA(x, y, p.fill)
+		}
+	}
+	return nil
+}
+
+func (p *fakePage) Name() string                     { return p.name }
+func (p *fakePage) PreferredDuration() time.Duration { return time.Second }
+func (p *fakePage) ScreenText() [][]byte             { return p.text }
+
+func TestPageSet(t *testing.T) {
+	l := zaptest.NewLogger(t, zaptest.Level(zapcore.DebugLevel))
+	red := &fakePage{name: "red", fill: color.RGBA{R: 255, A: 255}, text: [][]byte{[]byte("red")}}
+	blue := &fakePage{name: "blue", fill: color.RGBA{B: 255, A: 255}, text: [][]byte{[]byte("blue")}}
+	ps := NewPageSet(&OutputConfig{Width: 4, Height: 4}, []Page{red, blue})
+
+	ctx := context.Background()
+	if err := ps.UpdateAll(ctx); err != nil {
+		t.Fatalf("UpdateAll: %v", err)
+	}
+
+	withLogger := func(req *http.Request) *http.Request {
+		return req.WithContext(ctxzap.ToContext(req.Context(), l))
+	}
+
+	t.Run("active page defaults to the first one", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := withLogger(httptest.NewRequest("GET", "/index.png", nil))
+		ps.ServeImage(rec, req)
+		if got, want := rec.Code, http.StatusOK; got != want {
+			t.Fatalf("status:\n  got: %v\n want: %v", got, want)
+		}
+	})
+
+	t.Run("page query param selects a specific page", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := withLogger(httptest.NewRequest("GET", "/index.json?page=1", nil))
+		ps.ServeJSON(rec, req)
+		if got, want := rec.Body.String(), "\"page\":\"blue\""; !strings.Contains(got, want) {
+			t.Errorf("body %q does not contain %q", got, want)
+		}
+	})
+
+	t.Run("named page route serves a pinned page", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := withLogger(httptest.NewRequest("GET", "/pages/blue.png", nil))
+		ps.ServeNamedImage(rec, req)
+		if got, want := rec.Code, http.StatusOK; got != want {
+			t.Fatalf("status:\n  got: %v\n want: %v", got, want)
+		}
+	})
+
+	t.Run("unknown named page 404s", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := withLogger(httptest.NewRequest("GET", "/pages/green.png", nil))
+		ps.ServeNamedImage(rec, req)
+		if got, want := rec.Code, http.StatusNotFound; got != want {
+			t.Errorf("status:\n  got: %v\n want: %v", got, want)
+		}
+	})
+
+	t.Run("advance rotates to the next page", func(t *testing.T) {
+		if got, want := ps.ActivePage().Name(), "red"; got != want {
+			t.Fatalf("active page:\n  got: %v\n want: %v", got, want)
+		}
+		ps.Advance()
+		if got, want := ps.ActivePage().Name(), "blue"; got != want {
+			t.Errorf("active page after advance:\n  got: %v\n want: %v", got, want)
+		}
+		ps.Advance()
+		if got, want := ps.ActivePage().Name(), "red"; got != want {
+			t.Errorf("active page after wraparound:\n  got: %v\n want: %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package prefetch
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ServeHTTP writes every registered source's Status as JSON, for diagnosing stale readings.
+func (s *Scheduler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(s.Statuses()); err != nil {
+		l := ctxzap.Extract(req.Context())
+		l.Info("error sending json to client", zap.Error(err))
+	}
+}
@@ -0,0 +1,123 @@
+// Package prefetch schedules source-specific fetches on their own cron cadence and caches the
+// results, so that rendering a page never itself triggers network I/O.
+package prefetch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// FetchFunc retrieves a fresh value for a named source. The value returned is cached as-is; Get
+// type-asserts it back to the type the caller expects.
+type FetchFunc func(ctx context.Context) (interface{}, error)
+
+// Status summarizes the health of one scheduled source, for operators diagnosing stale readings.
+type Status struct {
+	LastSuccess   time.Time `json:"last_success"`
+	LastError     string    `json:"last_error,omitempty"`
+	LastErrorTime time.Time `json:"last_error_time,omitempty"`
+	NextRun       time.Time `json:"next_run"`
+}
+
+type entry struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	value   interface{}
+	fetched time.Time
+	status  Status
+	id      cron.EntryID
+}
+
+// Scheduler runs a set of named FetchFuncs on their own cron schedules and caches their results.
+type Scheduler struct {
+	cron    *cron.Cron
+	timeout time.Duration
+	entries sync.Map // name -> *entry
+}
+
+// NewScheduler returns a Scheduler whose fetches are each bounded by timeout.
+func NewScheduler(timeout time.Duration) *Scheduler {
+	return &Scheduler{cron: cron.New(), timeout: timeout}
+}
+
+// Register schedules fetch to run on the given cron spec (e.g. "@every 1m"), caching its result
+// under name for up to ttl before Get considers it stale. Register must be called before Start.
+// A cron schedule only describes future ticks, so Register also fires an immediate fetch in the
+// background, letting Get return a fresh value well before the first scheduled tick elapses.
+func (s *Scheduler) Register(name, spec string, ttl time.Duration, fetch FetchFunc) error {
+	e := &entry{ttl: ttl}
+	run := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+		v, err := fetch(ctx)
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if err != nil {
+			e.status.LastError = err.Error()
+			e.status.LastErrorTime = time.Now()
+			return
+		}
+		e.value = v
+		e.fetched = time.Now()
+		e.status.LastSuccess = e.fetched
+	}
+	id, err := s.cron.AddFunc(spec, run)
+	if err != nil {
+		return err
+	}
+	e.id = id
+	s.entries.Store(name, e)
+	go run()
+	return nil
+}
+
+// Start begins running every registered fetch on its schedule.
+func (s *Scheduler) Start() { s.cron.Start() }
+
+// Stop halts the scheduler, waiting for any in-flight fetch to finish.
+func (s *Scheduler) Stop() { <-s.cron.Stop().Done() }
+
+// Get returns the most recently cached value for name, along with when it was fetched. It
+// reports false if name is unregistered, has never successfully fetched, has gone stale past its
+// TTL, or was cached as a different type than T.
+func Get[T any](s *Scheduler, name string) (T, time.Time, bool) {
+	var zero T
+	raw, ok := s.entries.Load(name)
+	if !ok {
+		return zero, time.Time{}, false
+	}
+	e := raw.(*entry)
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.fetched.IsZero() {
+		return zero, time.Time{}, false
+	}
+	if e.ttl > 0 && time.Since(e.fetched) > e.ttl {
+		return zero, e.fetched, false
+	}
+	v, ok := e.value.(T)
+	if !ok {
+		return zero, e.fetched, false
+	}
+	return v, e.fetched, true
+}
+
+// Statuses returns a snapshot of every registered source's health, keyed by name.
+func (s *Scheduler) Statuses() map[string]Status {
+	out := make(map[string]Status)
+	s.entries.Range(func(k, v interface{}) bool {
+		e := v.(*entry)
+		e.mu.RLock()
+		st := e.status
+		e.mu.RUnlock()
+		if entry := s.cron.Entry(e.id); entry.ID != 0 {
+			st.NextRun = entry.Next
+		}
+		out[k.(string)] = st
+		return true
+	})
+	return out
+}
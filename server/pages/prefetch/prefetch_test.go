@@ -0,0 +1,107 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetReturnsLastFetchedValue(t *testing.T) {
+	s := NewScheduler(time.Second)
+	var calls int32
+	if err := s.Register("greeting", "@every 10ms", time.Hour, func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return "hello", nil
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	s.Start()
+	defer s.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	got, ts, ok := Get[string](s, "greeting")
+	if !ok {
+		t.Fatal("Get: value not found")
+	}
+	if got != "hello" {
+		t.Errorf("value:\n  got:  %q\n want: %q", got, "hello")
+	}
+	if ts.IsZero() {
+		t.Error("timestamp: got zero value")
+	}
+}
+
+func TestGetUnregisteredOrWrongType(t *testing.T) {
+	s := NewScheduler(time.Second)
+	if _, _, ok := Get[string](s, "missing"); ok {
+		t.Error("Get on unregistered name: got ok=true, want false")
+	}
+
+	if err := s.Register("count", "@every 1h", time.Hour, func(ctx context.Context) (interface{}, error) {
+		return 42, nil
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, _, ok := Get[string](s, "count"); ok {
+		t.Error("Get with mismatched type: got ok=true, want false")
+	}
+}
+
+func TestGetStaleAfterTTL(t *testing.T) {
+	s := NewScheduler(time.Second)
+	if err := s.Register("flaky", "@every 10ms", 5*time.Millisecond, func(ctx context.Context) (interface{}, error) {
+		return 1, nil
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	s.cron.Start()
+	defer s.cron.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, _, ok := Get[int](s, "flaky"); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("value never became available")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	s.cron.Stop()
+	time.Sleep(10 * time.Millisecond)
+	if _, _, ok := Get[int](s, "flaky"); ok {
+		t.Error("Get after TTL elapsed: got ok=true, want false")
+	}
+}
+
+func TestStatusesReportsErrors(t *testing.T) {
+	s := NewScheduler(time.Second)
+	if err := s.Register("broken", "@every 10ms", time.Hour, func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("boom")
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	s.Start()
+	defer s.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if st := s.Statuses()["broken"]; st.LastError != "" {
+			if st.LastError != "boom" {
+				t.Errorf("LastError:\n  got:  %q\n want: %q", st.LastError, "boom")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("error never recorded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,99 @@
+package pages
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/mdp/smallfont"
+)
+
+// sparklineWidth is how many pixels of each row are given over to the history sparkline, per the
+// request that it run "after" each numeric reading.
+const sparklineWidth = 30
+
+// newBlankImage returns a w x h image filled with opaque black, ready to draw a page onto.
+func newBlankImage(w, h int) *image.RGBA {
+	bounds := image.Rect(0, 0, w, h)
+	img := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: 0, G: 0, B: 0, A: 255})
+		}
+	}
+	return img
+}
+
+// newSmallFont returns a smallfont context for drawing 5x8 text onto dst.
+func newSmallFont(dst *image.RGBA) smallfont.Context {
+	return smallfont.Context{
+		Dst:    dst,
+		StartX: 0,
+		StartY: 0,
+		Font:   smallfont.Font5x8,
+		Color:  image.White,
+	}
+}
+
+// drawSparkline renders samples as a 1-pixel-wide-per-bucket sparkline into rect on dst. NaN and
+// zero-sentinel samples (the convention used elsewhere in this package for "no reading yet") are
+// dropped before plotting. A flat line renders in the middle row of rect rather than at an edge.
+func drawSparkline(dst *image.RGBA, rect image.Rectangle, samples []float64) {
+	width := rect.Dx()
+	if width <= 0 {
+		return
+	}
+	var clean []float64
+	for _, s := range samples {
+		if s == 0 || math.IsNaN(s) {
+			continue
+		}
+		clean = append(clean, s)
+	}
+	if len(clean) == 0 {
+		return
+	}
+
+	min, max := clean[0], clean[0]
+	for _, s := range clean {
+		if s < min {
+			min = s
+		}
+		if s > max {
+			max = s
+		}
+	}
+	pad := (max - min) * 0.1
+	if pad == 0 {
+		pad = 1
+	}
+	min -= pad
+	max += pad
+	span := max - min
+
+	sums := make([]float64, width)
+	counts := make([]int, width)
+	for i, s := range clean {
+		col := i * width / len(clean)
+		if col >= width {
+			col = width - 1
+		}
+		sums[col] += s
+		counts[col]++
+	}
+
+	for col := 0; col < width; col++ {
+		if counts[col] == 0 {
+			continue
+		}
+		mean := sums[col] / float64(counts[col])
+		frac := (mean - min) / span
+		y := rect.Max.Y - 1 - int(frac*float64(rect.Dy()-1))
+		if y < rect.Min.Y {
+			y = rect.Min.Y
+		} else if y >= rect.Max.Y {
+			y = rect.Max.Y - 1
+		}
+		dst.Set(rect.Min.X+col, y, color.White)
+	}
+}
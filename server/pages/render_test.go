@@ -0,0 +1,33 @@
+package pages
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDrawSparkline(t *testing.T) {
+	rect := image.Rect(0, 0, 10, 8)
+
+	t.Run("flat line renders in the middle row", func(t *testing.T) {
+		img := image.NewRGBA(rect)
+		drawSparkline(img, rect, []float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 5})
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if got, want := img.RGBAAt(x, 4), (color.RGBA{R: 255, G: 255, B: 255, A: 255}); got != want {
+				t.Errorf("pixel (%d, 4):\n  got: %v\n want: %v", x, got, want)
+			}
+		}
+	})
+
+	t.Run("zero and NaN samples are dropped", func(t *testing.T) {
+		img := image.NewRGBA(rect)
+		drawSparkline(img, rect, nil)
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			for y := rect.Min.Y; y < rect.Max.Y; y++ {
+				if got, want := img.RGBAAt(x, y), (color.RGBA{}); got != want {
+					t.Errorf("pixel (%d, %d):\n  got: %v\n want: %v", x, y, got, want)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	mqttPublishes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mqtt_sink_publishes_total",
+		Help: "Count of MQTT publish attempts, by topic and result.",
+	}, []string{"topic", "result"})
+	mqttConnected = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mqtt_sink_connected",
+		Help: "Whether the MQTT sink currently has a connection to its broker (1) or not (0).",
+	})
+)
+
+// MQTTConfig configures the MQTT sink. Leave BrokerURL empty to disable it.
+type MQTTConfig struct {
+	BrokerURL          string `long:"mqtt_broker_url" env:"MQTT_BROKER_URL" description:"The URL of the MQTT broker to publish rendered pages to (e.g. tcp://host:1883 or tls://host:8883). Leave empty to disable the MQTT sink."`
+	ClientID           string `long:"mqtt_client_id" env:"MQTT_CLIENT_ID" default:"display" description:"The MQTT client ID to connect with."`
+	TopicPrefix        string `long:"mqtt_topic_prefix" env:"MQTT_TOPIC_PREFIX" default:"display" description:"The topic prefix to publish image.png, image.raw, and text under."`
+	Retain             bool   `long:"mqtt_retain" env:"MQTT_RETAIN" description:"Whether to set the MQTT retain flag on published messages."`
+	QoS                int    `long:"mqtt_qos" env:"MQTT_QOS" default:"0" description:"The MQTT QoS level to publish at (0, 1, or 2)."`
+	InsecureSkipVerify bool   `long:"mqtt_insecure_skip_verify" env:"MQTT_INSECURE_SKIP_VERIFY" description:"Skip TLS certificate verification when connecting to the broker."`
+}
+
+// MQTT publishes rendered pages to an MQTT broker, for clients (e-ink or OLED panels, typically)
+// that would rather subscribe than poll the HTTP handlers.
+type MQTT struct {
+	cfg    *MQTTConfig
+	client mqtt.Client
+}
+
+// NewMQTT connects to the broker described by cfg and returns a Sink that publishes to it. The
+// underlying client reconnects automatically, with exponential backoff, if the connection drops.
+func NewMQTT(cfg *MQTTConfig) (*MQTT, error) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.BrokerURL).
+		SetClientID(cfg.ClientID).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetMaxReconnectInterval(5 * time.Minute).
+		SetOnConnectHandler(func(mqtt.Client) {
+			mqttConnected.Set(1)
+			zap.L().Info("mqtt sink connected", zap.String("broker", cfg.BrokerURL))
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			mqttConnected.Set(0)
+			zap.L().Warn("mqtt sink lost connection", zap.Error(err))
+		})
+	if cfg.InsecureSkipVerify {
+		opts.SetTLSConfig(&tls.Config{InsecureSkipVerify: true})
+	}
+
+	client := mqtt.NewClient(opts)
+	tok := client.Connect()
+	if !tok.WaitTimeout(10 * time.Second) {
+		return nil, fmt.Errorf("connect to %s: timed out", cfg.BrokerURL)
+	}
+	if err := tok.Error(); err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", cfg.BrokerURL, err)
+	}
+	return &MQTT{cfg: cfg, client: client}, nil
+}
+
+func (m *MQTT) publish(topic string, payload []byte) error {
+	tok := m.client.Publish(topic, byte(m.cfg.QoS), m.cfg.Retain, payload)
+	if !tok.WaitTimeout(5 * time.Second) {
+		mqttPublishes.WithLabelValues(topic, "timeout").Inc()
+		return fmt.Errorf("publish %s: timed out", topic)
+	}
+	if err := tok.Error(); err != nil {
+		mqttPublishes.WithLabelValues(topic, "error").Inc()
+		return fmt.Errorf("publish %s: %w", topic, err)
+	}
+	mqttPublishes.WithLabelValues(topic, "success").Inc()
+	return nil
+}
+
+// Publish sends img as a PNG and as packed 1-bit-per-pixel column-major bytes, and screenText as
+// JSON, to <prefix>/image.png, <prefix>/image.raw, and <prefix>/text respectively.
+func (m *MQTT) Publish(ctx context.Context, img *image.RGBA, screenText [][]byte) error {
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, img); err != nil {
+		return fmt.Errorf("encode png: %w", err)
+	}
+
+	lines := make([]string, len(screenText))
+	for i, l := range screenText {
+		lines[i] = string(l)
+	}
+	text, err := json.Marshal(lines)
+	if err != nil {
+		return fmt.Errorf("marshal screen text: %w", err)
+	}
+
+	prefix := m.cfg.TopicPrefix
+	if err := m.publish(prefix+"/image.png", pngBuf.Bytes()); err != nil {
+		return err
+	}
+	if err := m.publish(prefix+"/image.raw", packed1BPP(img)); err != nil {
+		return err
+	}
+	if err := m.publish(prefix+"/text", text); err != nil {
+		return err
+	}
+	return nil
+}
+
+// packed1BPP packs img into 1-bit-per-pixel, column-major bytes: each byte covers 8 vertically
+// stacked pixels (LSB first) in one column of one 8-row "page", in the layout SSD1306-style
+// panels expect.
+func packed1BPP(img *image.RGBA) []byte {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	pages := (h + 7) / 8
+	out := make([]byte, 0, w*pages)
+	for page := 0; page < pages; page++ {
+		for x := 0; x < w; x++ {
+			var col byte
+			for bit := 0; bit < 8; bit++ {
+				y := page*8 + bit
+				if y >= h {
+					continue
+				}
+				r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+				if r != 0 || g != 0 || b != 0 {
+					col |= 1 << uint(bit)
+				}
+			}
+			out = append(out, col)
+		}
+	}
+	return out
+}
@@ -0,0 +1,25 @@
+package sinks
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPacked1BPP(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 8))
+	img.Set(0, 0, color.White)
+	img.Set(0, 7, color.White)
+	img.Set(1, 3, color.White)
+
+	got := packed1BPP(img)
+	want := []byte{0b10000001, 0b00001000}
+	if len(got) != len(want) {
+		t.Fatalf("length:\n  got: %v\n want: %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("byte %d:\n  got:  %08b\n want: %08b", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,13 @@
+// Package sinks publishes a rendered page to places other than the HTTP handlers in pages.
+package sinks
+
+import (
+	"context"
+	"image"
+)
+
+// Sink receives the display's current image and screen text after every update, so it can push
+// it somewhere (an MQTT broker, a file, a physical panel) instead of waiting to be polled.
+type Sink interface {
+	Publish(ctx context.Context, img *image.RGBA, screenText [][]byte) error
+}
@@ -0,0 +1,92 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// METAR reads outdoor temperature from the latest aviation weather observation for a station,
+// via the NOAA Aviation Weather Center API (https://aviationweather.gov/data/api/).
+type METAR struct {
+	Client    *http.Client
+	Endpoint  string
+	StationID string
+}
+
+// NewMETAR returns a Provider that reads METAR observations for the given ICAO station ID (e.g.
+// "KJFK").
+func NewMETAR(cl *http.Client, stationID string) *METAR {
+	return &METAR{
+		Client:    cl,
+		Endpoint:  "https://aviationweather.gov/api/data/metar",
+		StationID: stationID,
+	}
+}
+
+func (p *METAR) Name() string { return "metar" }
+
+type metarReport struct {
+	ObsTime float64 `json:"obsTime"`
+	TempC   float64 `json:"temp"`
+}
+
+func (p *METAR) get(ctx context.Context, hours int) ([]metarReport, error) {
+	query := url.Values{
+		"ids":    {p.StationID},
+		"format": {"json"},
+		"hours":  {fmt.Sprintf("%d", hours)},
+	}
+	u := p.Endpoint + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("do request: non-OK status: %v", res.Status)
+	}
+	var reports []metarReport
+	if err := json.NewDecoder(res.Body).Decode(&reports); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("no reports returned for station %s", p.StationID)
+	}
+	return reports, nil
+}
+
+func toObservation(r metarReport) Observation {
+	return Observation{
+		Time:         time.Unix(int64(r.ObsTime), 0).UTC(),
+		TemperatureF: r.TempC*1.8 + 32.0,
+	}
+}
+
+func (p *METAR) Current(ctx context.Context) (Observation, error) {
+	reports, err := p.get(ctx, 1)
+	if err != nil {
+		return Observation{}, err
+	}
+	// The API returns newest-first.
+	return toObservation(reports[0]), nil
+}
+
+func (p *METAR) LastHour(ctx context.Context) ([]Observation, error) {
+	reports, err := p.get(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	obs := make([]Observation, 0, len(reports))
+	for i := len(reports) - 1; i >= 0; i-- {
+		obs = append(obs, toObservation(reports[i]))
+	}
+	return obs, nil
+}
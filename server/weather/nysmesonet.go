@@ -0,0 +1,83 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jrockway/display/server/mesonet"
+)
+
+// NYSMesonet reads outdoor temperature from the New York State Mesonet. See
+// http://www.nysmesonet.org/about/data to determine whether or not you're allowed to use this.
+type NYSMesonet struct {
+	Client   *http.Client
+	Endpoint string
+	Station  string
+}
+
+// NewNYSMesonet returns a Provider that reads the given station from the NYS Mesonet.
+func NewNYSMesonet(cl *http.Client, station string) *NYSMesonet {
+	return &NYSMesonet{
+		Client:   cl,
+		Endpoint: "https://api.nysmesonet.org/data/dynserv/timeseries2",
+		Station:  station,
+	}
+}
+
+func (p *NYSMesonet) Name() string { return "nysmesonet" }
+
+func (p *NYSMesonet) observations(ctx context.Context) ([]Observation, error) {
+	now := time.Now()
+	req := &mesonet.Request{
+		Endpoint: p.Endpoint,
+		Dataset:  "nysm",
+		Start:    now.Add(-time.Hour),
+		End:      now,
+		Stations: []string{p.Station},
+		Variables: []mesonet.Variable{
+			{
+				ID:    "tair",
+				Units: "degF",
+			},
+		},
+	}
+	res, err := mesonet.Do(ctx, p.Client, req)
+	if err != nil {
+		return nil, fmt.Errorf("do: %w", err)
+	}
+	tair := res.Response.DataVars["tair"].FloatData
+	if len(tair) == 0 {
+		return nil, fmt.Errorf("no data points returned (datavars: %#v)", res.Response.DataVars)
+	}
+	times := res.Response.Coords["time"].TimeData
+	var obs []Observation
+	for i, t := range tair {
+		if t == 0 {
+			// Sometimes the most recent data point is returned as 0.
+			continue
+		}
+		o := Observation{TemperatureF: t}
+		if i < len(times) {
+			o.Time = times[i]
+		}
+		obs = append(obs, o)
+	}
+	if len(obs) == 0 {
+		return nil, fmt.Errorf("no non-zero data points in %d samples", len(tair))
+	}
+	return obs, nil
+}
+
+func (p *NYSMesonet) Current(ctx context.Context) (Observation, error) {
+	obs, err := p.observations(ctx)
+	if err != nil {
+		return Observation{}, err
+	}
+	return obs[len(obs)-1], nil
+}
+
+func (p *NYSMesonet) LastHour(ctx context.Context) ([]Observation, error) {
+	return p.observations(ctx)
+}
@@ -0,0 +1,58 @@
+package weather
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+type testTransport struct {
+	body []byte
+}
+
+func (t *testTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(t.body)),
+	}, nil
+}
+
+// nysmesonetFixture is a trimmed-down NYS Mesonet timeseries2 response: two tair readings,
+// scaled by 0.1, for a single station.
+const nysmesonetFixture = `{
+	"success": true,
+	"response": {
+		"attrs": {},
+		"coords": {
+			"time": {
+				"attrs": {"long_name": "time"},
+				"dims": ["time"],
+				"data": ["20260726T1200", "20260726T1230"]
+			}
+		},
+		"dims": {},
+		"data_vars": {
+			"tair": {
+				"attrs": {"scale_factor": 0.1},
+				"dims": ["station", "time"],
+				"data": [[686, 688]]
+			}
+		}
+	}
+}`
+
+func TestNYSMesonetCurrent(t *testing.T) {
+	cl := &http.Client{Transport: &testTransport{body: []byte(nysmesonetFixture)}}
+	p := NewNYSMesonet(cl, "bkln")
+	obs, err := p.Current(context.Background())
+	if err != nil {
+		t.Fatalf("current: %v", err)
+	}
+	if got, want := obs.TemperatureF, 68.8; got != want {
+		t.Errorf("temperature:\n  got: %v\n want: %v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// OpenMeteo reads outdoor temperature from the free Open-Meteo forecast API. See
+// https://open-meteo.com/en/docs for details; it requires no API key.
+type OpenMeteo struct {
+	Client    *http.Client
+	Endpoint  string
+	Latitude  float64
+	Longitude float64
+}
+
+// NewOpenMeteo returns a Provider that reads the forecast for the given coordinates from
+// Open-Meteo.
+func NewOpenMeteo(cl *http.Client, lat, lon float64) *OpenMeteo {
+	return &OpenMeteo{
+		Client:    cl,
+		Endpoint:  "https://api.open-meteo.com/v1/forecast",
+		Latitude:  lat,
+		Longitude: lon,
+	}
+}
+
+func (p *OpenMeteo) Name() string { return "open-meteo" }
+
+type openMeteoResponse struct {
+	Current struct {
+		Time        string  `json:"time"`
+		Temperature float64 `json:"temperature_2m"`
+	} `json:"current"`
+	Hourly struct {
+		Time        []string  `json:"time"`
+		Temperature []float64 `json:"temperature_2m"`
+	} `json:"hourly"`
+}
+
+func (p *OpenMeteo) get(ctx context.Context, query url.Values) (*openMeteoResponse, error) {
+	query.Set("latitude", fmt.Sprintf("%f", p.Latitude))
+	query.Set("longitude", fmt.Sprintf("%f", p.Longitude))
+	query.Set("temperature_unit", "fahrenheit")
+	query.Set("timezone", "auto")
+	u := p.Endpoint + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("do request: non-OK status: %v", res.Status)
+	}
+	var parsed openMeteoResponse
+	if err := json.NewDecoder(res.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &parsed, nil
+}
+
+func (p *OpenMeteo) Current(ctx context.Context) (Observation, error) {
+	res, err := p.get(ctx, url.Values{"current": {"temperature_2m"}})
+	if err != nil {
+		return Observation{}, err
+	}
+	t, err := time.Parse("2006-01-02T15:04", res.Current.Time)
+	if err != nil {
+		return Observation{}, fmt.Errorf("parse current time %q: %w", res.Current.Time, err)
+	}
+	return Observation{Time: t, TemperatureF: res.Current.Temperature}, nil
+}
+
+func (p *OpenMeteo) LastHour(ctx context.Context) ([]Observation, error) {
+	res, err := p.get(ctx, url.Values{"hourly": {"temperature_2m"}, "past_hours": {"1"}, "forecast_hours": {"0"}})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Hourly.Time) == 0 {
+		return nil, fmt.Errorf("no hourly samples returned")
+	}
+	obs := make([]Observation, 0, len(res.Hourly.Time))
+	for i, ts := range res.Hourly.Time {
+		t, err := time.Parse("2006-01-02T15:04", ts)
+		if err != nil {
+			return nil, fmt.Errorf("parse hourly time %q: %w", ts, err)
+		}
+		obs = append(obs, Observation{Time: t, TemperatureF: res.Hourly.Temperature[i]})
+	}
+	return obs, nil
+}
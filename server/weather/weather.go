@@ -0,0 +1,43 @@
+// Package weather defines a common interface for outdoor weather data sources.
+//
+// The display originally only knew how to talk to the New York State Mesonet, which meant the
+// module was useless to anyone living outside New York. Providers let us plug in other upstreams
+// and fall back between them when one is degraded.
+package weather
+
+import (
+	"context"
+	"time"
+)
+
+// Observation is a single outdoor weather reading.
+type Observation struct {
+	Time         time.Time
+	TemperatureF float64
+}
+
+// Provider is a source of outdoor weather observations.
+type Provider interface {
+	// Name identifies the provider, for logging and metrics.
+	Name() string
+
+	// Current returns the most recent observation available.
+	Current(ctx context.Context) (Observation, error)
+
+	// LastHour returns whatever observations are available from roughly the past hour, oldest
+	// first. Providers that can't produce history should return an error rather than a
+	// suspiciously short or empty slice.
+	LastHour(ctx context.Context) ([]Observation, error)
+}
+
+// Mean returns the arithmetic mean of the temperatures in obs. It returns 0 for an empty slice.
+func Mean(obs []Observation) float64 {
+	if len(obs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, o := range obs {
+		sum += o.TemperatureF
+	}
+	return sum / float64(len(obs))
+}
@@ -0,0 +1,73 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Wttr reads current outdoor temperature from wttr.in's JSON API
+// (https://github.com/chubin/wttr.in), a thin scraper over several public weather services. It's
+// a reasonable provider of last resort since it needs no API key and works for any place name.
+type Wttr struct {
+	Client   *http.Client
+	Endpoint string
+	Location string
+}
+
+// NewWttr returns a Provider that reads the current conditions for the given location (a place
+// name, postal code, or "lat,lon" pair) from wttr.in.
+func NewWttr(cl *http.Client, location string) *Wttr {
+	return &Wttr{
+		Client:   cl,
+		Endpoint: "https://wttr.in",
+		Location: location,
+	}
+}
+
+func (p *Wttr) Name() string { return "wttr.in" }
+
+type wttrResponse struct {
+	CurrentCondition []struct {
+		ObservationTime string `json:"observation_time"`
+		TempF           string `json:"temp_F"`
+	} `json:"current_condition"`
+}
+
+func (p *Wttr) Current(ctx context.Context) (Observation, error) {
+	u := fmt.Sprintf("%s/%s?format=j1", p.Endpoint, p.Location)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return Observation{}, fmt.Errorf("new request: %w", err)
+	}
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return Observation{}, fmt.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Observation{}, fmt.Errorf("do request: non-OK status: %v", res.Status)
+	}
+	var parsed wttrResponse
+	if err := json.NewDecoder(res.Body).Decode(&parsed); err != nil {
+		return Observation{}, fmt.Errorf("decode response: %w", err)
+	}
+	if len(parsed.CurrentCondition) == 0 {
+		return Observation{}, errors.New("no current_condition in response")
+	}
+	cur := parsed.CurrentCondition[0]
+	f, err := strconv.ParseFloat(cur.TempF, 64)
+	if err != nil {
+		return Observation{}, fmt.Errorf("parse temp_F %q: %w", cur.TempF, err)
+	}
+	return Observation{Time: time.Now(), TemperatureF: f}, nil
+}
+
+// LastHour always fails: wttr.in reports current conditions and a forecast, not recent history.
+func (p *Wttr) LastHour(ctx context.Context) ([]Observation, error) {
+	return nil, errors.New("wttr.in does not provide historical observations")
+}